@@ -0,0 +1,84 @@
+package push
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// NewAttachmentFromFile reads path, detects its MIME type and returns a
+// regular (non-inline) Attachment ready to append to BodyCommon.Attachments.
+func NewAttachmentFromFile(path string) (*Attachment, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment %s: %v", path, err)
+	}
+	return &Attachment{
+		Content:     base64.StdEncoding.EncodeToString(bs),
+		Filename:    filepath.Base(path),
+		Type:        contentType(bs),
+		Disposition: "attachment",
+	}, nil
+}
+
+// NewInlineAttachment reads r and returns an inline Attachment referenced
+// from HTML content via "cid:<cid>", e.g. for images embedded in the body.
+func NewInlineAttachment(r io.Reader, filename, cid string) (*Attachment, error) {
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inline attachment %s: %v", filename, err)
+	}
+	return &Attachment{
+		Content:     base64.StdEncoding.EncodeToString(bs),
+		Filename:    filename,
+		Type:        contentType(bs),
+		Disposition: "inline",
+		ContentID:   cid,
+	}, nil
+}
+
+// contentType is a small wrapper around http.DetectContentType kept for
+// callers that want to inspect an attachment's detected type before
+// building it, e.g. to reject disallowed types.
+func contentType(bs []byte) string {
+	return http.DetectContentType(bs)
+}
+
+var cidImgSrc = regexp.MustCompile(`(?i)<img[^>]*\ssrc=["']cid:([^"']+)["']`)
+
+// SetHTMLWithInlineImages sets Content.HTML to html and appends one inline
+// Attachment per entry in images. Keys of images must match the cid
+// referenced in html, e.g. `<img src="cid:logo">` pairs with
+// images["logo"]; a cid referenced in html with no matching entry in
+// images is reported as an error instead of being sent broken.
+func (e *RegularEmail) SetHTMLWithInlineImages(html string, images map[string]io.Reader) error {
+	for _, m := range cidImgSrc.FindAllStringSubmatch(html, -1) {
+		if _, ok := images[m[1]]; !ok {
+			return fmt.Errorf("html references cid:%s but no matching image was provided", m[1])
+		}
+	}
+
+	attachments := make([]*Attachment, 0, len(images))
+	for key, r := range images {
+		att, err := NewInlineAttachment(r, key, key)
+		if err != nil {
+			return err
+		}
+		attachments = append(attachments, att)
+	}
+
+	if e.Content == nil {
+		e.Content = &EmailContent{}
+	}
+	e.Content.HTML = html
+
+	if e.BodyCommon == nil {
+		e.BodyCommon = &BodyCommon{}
+	}
+	e.BodyCommon.Attachments = append(e.BodyCommon.Attachments, attachments...)
+	return nil
+}
@@ -0,0 +1,124 @@
+package push
+
+import (
+	"fmt"
+	"sync"
+)
+
+// maxBulkRecipients is the documented `to` limit per send call.
+const maxBulkRecipients = 100
+
+// bulkWorkers bounds how many batches are sent concurrently.
+const bulkWorkers = 4
+
+// Recipient is one address in a SendBulk call, with its own template
+// variables.
+type Recipient struct {
+	Email string
+
+	// Vars are substituted into `%var%` placeholders, same as
+	// BodyCommon.Vars but keyed per recipient instead of column-oriented.
+	Vars map[string]any
+
+	// DynamicVars are merged into BodyCommon.DynamicVars for this
+	// recipient's batch; since DynamicVars apply to the whole request
+	// rather than per-address, recipients sharing a batch should agree on
+	// these values.
+	DynamicVars map[string]any
+}
+
+// SendBulk sends template to each recipient with its own Vars, chunking
+// into batches of up to 100 addresses (the API's `to` limit) and sending
+// batches concurrently. It returns one *EmailResponse per batch, in the
+// order the batches were built; a failed batch's slot holds whatever
+// response Send returned (nil if no HTTP response was read) and its error
+// is folded into the returned error.
+func (c *EngageLabEmailClient) SendBulk(from string, recipients []Recipient, subject, template string) ([]*EmailResponse, error) {
+	batches := chunkRecipients(recipients, maxBulkRecipients)
+
+	responses := make([]*EmailResponse, len(batches))
+	errs := make([]error, len(batches))
+
+	sem := make(chan struct{}, bulkWorkers)
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		i, batch := i, batch
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			to, vars, dynamicVars := transposeRecipients(batch)
+			bodyCommon := &BodyCommon{Vars: vars, DynamicVars: dynamicVars}
+			rsp, err := c.Send(bodyCommon, from, to, subject, "", "", "", template, "")
+			responses[i] = rsp
+			if err != nil {
+				errs[i] = fmt.Errorf("batch %d (%d recipients): %v", i, len(batch), err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return responses, joinErrors(errs)
+}
+
+func chunkRecipients(recipients []Recipient, size int) [][]Recipient {
+	if len(recipients) == 0 {
+		return nil
+	}
+	var batches [][]Recipient
+	for size < len(recipients) {
+		recipients, batches = recipients[size:], append(batches, recipients[:size:size])
+	}
+	return append(batches, recipients)
+}
+
+// transposeRecipients turns per-recipient Vars into the API's
+// column-oriented Vars map[string][]any, whose slices must all have
+// len(to) entries aligned by recipient index. DynamicVars are merged
+// since the API applies them to the whole request, not per-address.
+func transposeRecipients(batch []Recipient) (to []string, vars map[string][]any, dynamicVars map[string]any) {
+	to = make([]string, len(batch))
+	keys := map[string]bool{}
+	for i, r := range batch {
+		to[i] = r.Email
+		for k := range r.Vars {
+			keys[k] = true
+		}
+	}
+
+	vars = map[string][]any{}
+	for k := range keys {
+		column := make([]any, len(batch))
+		for i, r := range batch {
+			column[i] = r.Vars[k]
+		}
+		vars[k] = column
+	}
+
+	dynamicVars = map[string]any{}
+	for _, r := range batch {
+		for k, v := range r.DynamicVars {
+			dynamicVars[k] = v
+		}
+	}
+	if len(dynamicVars) == 0 {
+		dynamicVars = nil
+	}
+
+	return to, vars, dynamicVars
+}
+
+func joinErrors(errs []error) error {
+	var msgs []string
+	for _, err := range errs {
+		if err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d batches failed: %v", len(msgs), len(errs), msgs)
+}
@@ -0,0 +1,62 @@
+package push
+
+import "testing"
+
+func TestChunkRecipientsEmpty(t *testing.T) {
+	batches := chunkRecipients(nil, maxBulkRecipients)
+	if len(batches) != 0 {
+		t.Fatalf("chunkRecipients(nil) = %v, want no batches", batches)
+	}
+}
+
+func TestChunkRecipientsSplitsAtSize(t *testing.T) {
+	recipients := make([]Recipient, 250)
+	for i := range recipients {
+		recipients[i] = Recipient{Email: "x@example.com"}
+	}
+
+	batches := chunkRecipients(recipients, 100)
+
+	if len(batches) != 3 {
+		t.Fatalf("got %d batches, want 3", len(batches))
+	}
+	if len(batches[0]) != 100 || len(batches[1]) != 100 || len(batches[2]) != 50 {
+		t.Fatalf("batch sizes = %d/%d/%d, want 100/100/50", len(batches[0]), len(batches[1]), len(batches[2]))
+	}
+}
+
+func TestTransposeRecipients(t *testing.T) {
+	batch := []Recipient{
+		{Email: "a@example.com", Vars: map[string]any{"uid": 1, "name": "Amos"}},
+		{Email: "b@example.com", Vars: map[string]any{"uid": 2}},
+	}
+
+	to, vars, dynamicVars := transposeRecipients(batch)
+
+	if len(to) != 2 || to[0] != "a@example.com" || to[1] != "b@example.com" {
+		t.Fatalf("to = %v, want [a@example.com b@example.com]", to)
+	}
+	if len(vars["uid"]) != 2 || vars["uid"][0] != 1 || vars["uid"][1] != 2 {
+		t.Fatalf("vars[uid] = %v, want [1 2] aligned to `to`", vars["uid"])
+	}
+	// "name" is only set for the first recipient; the column must still be
+	// len(to)-aligned, with a nil placeholder for the second.
+	if len(vars["name"]) != 2 || vars["name"][0] != "Amos" || vars["name"][1] != nil {
+		t.Fatalf("vars[name] = %v, want [Amos <nil>]", vars["name"])
+	}
+	if dynamicVars != nil {
+		t.Fatalf("dynamicVars = %v, want nil when no recipient sets any", dynamicVars)
+	}
+}
+
+func TestTransposeRecipientsMergesDynamicVars(t *testing.T) {
+	batch := []Recipient{
+		{Email: "a@example.com", DynamicVars: map[string]any{"campaign": "spring"}},
+	}
+
+	_, _, dynamicVars := transposeRecipients(batch)
+
+	if dynamicVars["campaign"] != "spring" {
+		t.Fatalf("dynamicVars = %v, want campaign=spring", dynamicVars)
+	}
+}
@@ -0,0 +1,104 @@
+// Command engagelab-emailctl is an operator tool for validating an
+// EngageLab configuration without writing a throwaway _test.go: send a
+// sandboxed canary, list templates, or render one locally.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	push "github.com/weaming/engage-lab-email-client"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "test-send":
+		cmdTestSend(os.Args[2:])
+	case "list-templates":
+		cmdListTemplates(os.Args[2:])
+	case "render-template":
+		cmdRenderTemplate(os.Args[2:])
+	default:
+		usage()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: engagelab-emailctl <test-send|list-templates|render-template> [flags]")
+}
+
+func cmdTestSend(args []string) {
+	fs := flag.NewFlagSet("test-send", flag.ExitOnError)
+	to := fs.String("to", "", "recipient address")
+	apiKey := fs.String("api-key", "", "EngageLab API key (defaults to ENGAGE_LAB_EMAIL_API_KEY)")
+	fs.Parse(args)
+
+	if *to == "" {
+		fmt.Fprintln(os.Stderr, "missing -to")
+		os.Exit(1)
+	}
+
+	client := push.NewEngageLabEmailClient(*apiKey)
+	rsp, err := client.TestSend(*to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "test-send failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(rsp.Json())
+}
+
+func cmdListTemplates(args []string) {
+	fs := flag.NewFlagSet("list-templates", flag.ExitOnError)
+	apiKey := fs.String("api-key", "", "EngageLab API key (defaults to ENGAGE_LAB_EMAIL_API_KEY)")
+	fs.Parse(args)
+
+	client := push.NewEngageLabEmailClient(*apiKey)
+	templates, err := client.GetTemplates()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "list-templates failed: %v\n", err)
+		os.Exit(1)
+	}
+	for _, t := range templates {
+		fmt.Printf("%s\t%s\n", t.TemplateInvokeName, t.Name)
+	}
+}
+
+func cmdRenderTemplate(args []string) {
+	fs := flag.NewFlagSet("render-template", flag.ExitOnError)
+	name := fs.String("name", "", "template invoke name")
+	varsJSON := fs.String("vars", "{}", "JSON object of template vars, e.g. '{\"uid\":1}'")
+	apiKey := fs.String("api-key", "", "EngageLab API key (defaults to ENGAGE_LAB_EMAIL_API_KEY)")
+	fs.Parse(args)
+
+	if *name == "" {
+		fmt.Fprintln(os.Stderr, "missing -name")
+		os.Exit(1)
+	}
+
+	var vars map[string]any
+	if err := json.Unmarshal([]byte(*varsJSON), &vars); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -vars JSON: %v\n", err)
+		os.Exit(1)
+	}
+
+	client := push.NewEngageLabEmailClient(*apiKey)
+	subject, html, text, err := client.RenderTemplateLocal(*name, vars)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "render-template failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	body := html
+	if body == "" {
+		body = text
+	}
+	fmt.Printf("Subject: %s\n\n%s\n", subject, body)
+}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -119,6 +120,7 @@ type EmailContent struct {
 type Attachment struct {
 	Content     string `json:"content,omitempty"`
 	Filename    string `json:"filename,omitempty"`
+	Type        string `json:"type,omitempty"` // MIME type, e.g. "image/png"
 	Disposition string `json:"disposition,omitempty"`
 	ContentID   string `json:"content_id,omitempty"`
 }
@@ -147,6 +149,9 @@ type Settings struct {
 type EngageLabEmailClient struct {
 	apiKey string
 	client *http.Client
+
+	cacheOnce sync.Once
+	cache     *TemplateCache
 }
 
 func NewEngageLabEmailClient(apiKey string) *EngageLabEmailClient {
@@ -0,0 +1,113 @@
+package push
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+)
+
+// MultiProvider tries each Provider in order, falling back to the next one
+// when the current provider fails with a network error or timeout, or
+// returns a 429 or 5xx response (see shouldFailover). It does not retry
+// other 4xx responses since those indicate the request itself is bad.
+type MultiProvider struct {
+	providers []Provider
+}
+
+// NewMultiProvider builds a MultiProvider that tries providers in the given
+// order, e.g. NewMultiProvider(engageLabClient, smtpProvider).
+func NewMultiProvider(providers ...Provider) *MultiProvider {
+	return &MultiProvider{providers: providers}
+}
+
+func (m *MultiProvider) Name() string {
+	return "multi"
+}
+
+func (m *MultiProvider) SendRegular(
+	from string,
+	to []string,
+	subject string,
+	text, html string,
+	reqId string,
+) (*EmailResponse, error) {
+	var lastErr error
+	for _, p := range m.providers {
+		rsp, err := p.SendRegular(from, to, subject, text, html, reqId)
+		if !shouldFailover(rsp, err) {
+			return rsp, err
+		}
+		lastErr = failoverErr(rsp, err)
+		log.Printf("provider %s failed, falling back: %v", p.Name(), lastErr)
+	}
+	return nil, lastErr
+}
+
+func (m *MultiProvider) SendTemplate(
+	from string,
+	to []string,
+	subject string,
+	template string,
+	vars map[string][]any,
+	reqId string,
+) (*EmailResponse, error) {
+	var lastErr error
+	for _, p := range m.providers {
+		rsp, err := p.SendTemplate(from, to, subject, template, vars, reqId)
+		if !shouldFailover(rsp, err) {
+			return rsp, err
+		}
+		lastErr = failoverErr(rsp, err)
+		log.Printf("provider %s failed, falling back: %v", p.Name(), lastErr)
+	}
+	return nil, lastErr
+}
+
+// GetTemplates returns the templates of the first provider that can list them.
+func (m *MultiProvider) GetTemplates() ([]*Template, error) {
+	var lastErr error
+	for _, p := range m.providers {
+		xs, err := p.GetTemplates()
+		if err == nil {
+			return xs, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// shouldFailover reports whether the next provider should be tried: network
+// errors/timeouts, 429 (rate-limited) and 5xx responses are retryable,
+// everything else is not. A 429/5xx is a successful HTTP round-trip, so it
+// comes back as a non-nil rsp with a nil err — the status must be checked
+// independently of err.
+func shouldFailover(rsp *EmailResponse, err error) bool {
+	if rsp != nil && (rsp.HTTPStatus == http.StatusTooManyRequests || rsp.HTTPStatus >= 500) {
+		return true
+	}
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return false
+}
+
+// failoverErr returns err if non-nil, otherwise synthesizes one from rsp's
+// HTTP status. A retryable 429/5xx is a successful HTTP round-trip, so err
+// is nil in that case and callers that need a real error (to record as
+// LastError, wrap with %w, or return instead of a bare nil) must not
+// assume shouldFailover==true implies err != nil.
+func failoverErr(rsp *EmailResponse, err error) error {
+	if err != nil {
+		return err
+	}
+	if rsp == nil {
+		return fmt.Errorf("provider returned no response")
+	}
+	return fmt.Errorf("provider returned HTTP %d", rsp.HTTPStatus)
+}
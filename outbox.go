@@ -0,0 +1,298 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxRequest is the JSON-serializable form of a send call, persisted by
+// a Store while a message waits for its next retry.
+type OutboxRequest struct {
+	Kind string `json:"kind"` // "regular" or "template"
+
+	From    string   `json:"from"`
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+
+	// Regular-send fields.
+	Text string `json:"text,omitempty"`
+	HTML string `json:"html,omitempty"`
+
+	// Template-send fields.
+	Template string           `json:"template,omitempty"`
+	Vars     map[string][]any `json:"vars,omitempty"`
+
+	RequestId string `json:"request_id"`
+}
+
+// OutboxItem is a queued OutboxRequest plus its retry bookkeeping.
+type OutboxItem struct {
+	ID          string        `json:"id"`
+	Request     OutboxRequest `json:"request"`
+	Attempt     int           `json:"attempt"`
+	NextRetryAt time.Time     `json:"next_retry_at"`
+	LastError   string        `json:"last_error,omitempty"`
+}
+
+// Store persists OutboxItems across process restarts. MemoryStore and
+// BoltStore are the built-in implementations.
+type Store interface {
+	Enqueue(item *OutboxItem) error
+	// Due returns queued items whose NextRetryAt has passed.
+	Due(now time.Time) ([]*OutboxItem, error)
+	Update(item *OutboxItem) error
+	Remove(id string) error
+	// Count returns the total number of queued items, due or not; used to
+	// seed OutboxStats.Queued when an Outbox is built on a Store that
+	// already has a backlog from a previous process.
+	Count() (int, error)
+}
+
+// MemoryStore is an in-memory Store. It does not survive process restarts
+// and is mainly useful for tests and single-process deployments that can
+// tolerate losing queued mail on crash.
+type MemoryStore struct {
+	mu    sync.Mutex
+	items map[string]*OutboxItem
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: map[string]*OutboxItem{}}
+}
+
+func (s *MemoryStore) Enqueue(item *OutboxItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[item.ID] = item
+	return nil
+}
+
+func (s *MemoryStore) Due(now time.Time) ([]*OutboxItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	xs := []*OutboxItem{}
+	for _, item := range s.items {
+		if !item.NextRetryAt.After(now) {
+			xs = append(xs, item)
+		}
+	}
+	return xs, nil
+}
+
+func (s *MemoryStore) Update(item *OutboxItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[item.ID] = item
+	return nil
+}
+
+func (s *MemoryStore) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.items, id)
+	return nil
+}
+
+func (s *MemoryStore) Count() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items), nil
+}
+
+// OutboxStats reports queue depth for observability/health checks. Queued
+// is the number of items currently waiting in the store; Sent and Failed
+// count items that left the queue after a successful retry or after being
+// abandoned.
+type OutboxStats struct {
+	Queued int
+	Sent   int
+	Failed int
+}
+
+// Outbox wraps a Provider so that transient failures (timeouts, 429, 5xx)
+// are queued to a Store and retried with exponential backoff + jitter; the
+// caller still gets the send error, wrapped to say it was queued. Permanent
+// failures (after MaxAttempts, or a non-retryable error on a retry) are
+// handed to OnPermanentFailure if set.
+type Outbox struct {
+	provider    Provider
+	store       Store
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+
+	// OnPermanentFailure is called once an item exhausts MaxAttempts, or
+	// when a retry fails with a non-retryable error.
+	OnPermanentFailure func(item *OutboxItem, err error)
+
+	mu    sync.Mutex
+	stats OutboxStats
+}
+
+// NewOutbox wraps provider with store-backed retry. store may be a
+// *MemoryStore or *BoltStore (or any custom Store implementation). If
+// store already holds a backlog from a previous process, Queued starts
+// seeded from it instead of zero.
+func NewOutbox(provider Provider, store Store) *Outbox {
+	o := &Outbox{
+		provider:    provider,
+		store:       store,
+		MaxAttempts: 8,
+		BaseDelay:   time.Second,
+		MaxDelay:    5 * time.Minute,
+	}
+	if n, err := store.Count(); err == nil {
+		o.stats.Queued = n
+	} else {
+		log.Printf("outbox: failed to read initial queue depth: %v", err)
+	}
+	return o
+}
+
+func (o *Outbox) SendRegular(from string, to []string, subject string, text, html string, reqId string) (*EmailResponse, error) {
+	if reqId == "" {
+		reqId = newRequestId()
+	}
+	rsp, err := o.provider.SendRegular(from, to, subject, text, html, reqId)
+	if !shouldFailover(rsp, err) {
+		return rsp, err
+	}
+	return rsp, o.enqueue(OutboxRequest{
+		Kind: "regular", From: from, To: to, Subject: subject, Text: text, HTML: html, RequestId: reqId,
+	}, failoverErr(rsp, err))
+}
+
+func (o *Outbox) SendTemplate(from string, to []string, subject string, template string, vars map[string][]any, reqId string) (*EmailResponse, error) {
+	if reqId == "" {
+		reqId = newRequestId()
+	}
+	rsp, err := o.provider.SendTemplate(from, to, subject, template, vars, reqId)
+	if !shouldFailover(rsp, err) {
+		return rsp, err
+	}
+	return rsp, o.enqueue(OutboxRequest{
+		Kind: "template", From: from, To: to, Subject: subject, Template: template, Vars: vars, RequestId: reqId,
+	}, failoverErr(rsp, err))
+}
+
+func (o *Outbox) enqueue(req OutboxRequest, sendErr error) error {
+	item := &OutboxItem{
+		ID:          req.RequestId,
+		Request:     req,
+		Attempt:     1,
+		NextRetryAt: time.Now().Add(o.nextDelay(1)),
+		LastError:   sendErr.Error(),
+	}
+	if err := o.store.Enqueue(item); err != nil {
+		return fmt.Errorf("send failed (%v) and could not be queued for retry: %v", sendErr, err)
+	}
+	o.mu.Lock()
+	o.stats.Queued++
+	o.mu.Unlock()
+	return fmt.Errorf("%w: queued for retry as %s", sendErr, item.ID)
+}
+
+// nextDelay computes an exponential delay capped at MaxDelay, then applies
+// +/-50% jitter, so the result can reach up to 1.5x MaxDelay.
+func (o *Outbox) nextDelay(attempt int) time.Duration {
+	delay := o.BaseDelay * time.Duration(1<<uint(attempt-1))
+	if delay > o.MaxDelay || delay <= 0 {
+		delay = o.MaxDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay))) - delay/2
+	return delay + jitter
+}
+
+// Start drains due items in the background until ctx is canceled.
+func (o *Outbox) Start(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				o.drainOnce()
+			}
+		}
+	}()
+}
+
+func (o *Outbox) drainOnce() {
+	due, err := o.store.Due(time.Now())
+	if err != nil {
+		log.Printf("outbox: failed to list due items: %v", err)
+		return
+	}
+	for _, item := range due {
+		o.retry(item)
+	}
+}
+
+func (o *Outbox) retry(item *OutboxItem) {
+	req := item.Request
+	var (
+		rsp *EmailResponse
+		err error
+	)
+	switch req.Kind {
+	case "template":
+		rsp, err = o.provider.SendTemplate(req.From, req.To, req.Subject, req.Template, req.Vars, req.RequestId)
+	default:
+		rsp, err = o.provider.SendRegular(req.From, req.To, req.Subject, req.Text, req.HTML, req.RequestId)
+	}
+
+	if !shouldFailover(rsp, err) {
+		if err == nil {
+			_ = o.store.Remove(item.ID)
+			o.mu.Lock()
+			o.stats.Queued--
+			o.stats.Sent++
+			o.mu.Unlock()
+			return
+		}
+		// Permanent, non-retryable failure (e.g. 4xx): stop retrying now.
+		o.abandon(item, err)
+		return
+	}
+
+	err = failoverErr(rsp, err)
+	item.Attempt++
+	item.LastError = err.Error()
+	if item.Attempt >= o.MaxAttempts {
+		o.abandon(item, err)
+		return
+	}
+	item.NextRetryAt = time.Now().Add(o.nextDelay(item.Attempt))
+	if err := o.store.Update(item); err != nil {
+		log.Printf("outbox: failed to persist retry state for %s: %v", item.ID, err)
+	}
+}
+
+func (o *Outbox) abandon(item *OutboxItem, err error) {
+	_ = o.store.Remove(item.ID)
+	o.mu.Lock()
+	o.stats.Queued--
+	o.stats.Failed++
+	o.mu.Unlock()
+	if o.OnPermanentFailure != nil {
+		o.OnPermanentFailure(item, err)
+	}
+}
+
+func (o *Outbox) Stats() OutboxStats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.stats
+}
+
+func newRequestId() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,90 @@
+package push
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var outboxBucket = []byte("outbox")
+
+// BoltStore is a Store backed by a single BoltDB file, so queued mail
+// survives process restarts. Use MemoryStore instead for tests.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (creating if needed) a BoltDB file at path.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox db %s: %v", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(outboxBucket)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create outbox bucket: %v", err)
+	}
+	return &BoltStore{db: db}, nil
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *BoltStore) Enqueue(item *OutboxItem) error {
+	return s.put(item)
+}
+
+func (s *BoltStore) Update(item *OutboxItem) error {
+	return s.put(item)
+}
+
+func (s *BoltStore) put(item *OutboxItem) error {
+	bs, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox item: %v", err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(outboxBucket).Put([]byte(item.ID), bs)
+	})
+}
+
+func (s *BoltStore) Remove(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(outboxBucket).Delete([]byte(id))
+	})
+}
+
+func (s *BoltStore) Due(now time.Time) ([]*OutboxItem, error) {
+	xs := []*OutboxItem{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(outboxBucket).ForEach(func(k, v []byte) error {
+			item := &OutboxItem{}
+			if err := json.Unmarshal(v, item); err != nil {
+				return fmt.Errorf("failed to unmarshal outbox item %s: %v", k, err)
+			}
+			if !item.NextRetryAt.After(now) {
+				xs = append(xs, item)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return xs, nil
+}
+
+func (s *BoltStore) Count() (int, error) {
+	n := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		n = tx.Bucket(outboxBucket).Stats().KeyN
+		return nil
+	})
+	return n, err
+}
@@ -0,0 +1,192 @@
+package push
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeProvider returns a scripted sequence of (rsp, err) pairs, one per
+// call, then 200/nil for any call past the end of the script.
+type fakeProvider struct {
+	script []fakeResult
+	calls  int
+}
+
+type fakeResult struct {
+	rsp *EmailResponse
+	err error
+}
+
+func (p *fakeProvider) Name() string { return "fake" }
+
+func (p *fakeProvider) next() (*EmailResponse, error) {
+	if p.calls >= len(p.script) {
+		p.calls++
+		return &EmailResponse{HTTPStatus: 200}, nil
+	}
+	r := p.script[p.calls]
+	p.calls++
+	return r.rsp, r.err
+}
+
+func (p *fakeProvider) SendRegular(from string, to []string, subject string, text, html string, reqId string) (*EmailResponse, error) {
+	return p.next()
+}
+
+func (p *fakeProvider) SendTemplate(from string, to []string, subject string, template string, vars map[string][]any, reqId string) (*EmailResponse, error) {
+	return p.next()
+}
+
+func (p *fakeProvider) GetTemplates() ([]*Template, error) { return nil, nil }
+
+// A 429 comes back as a successful HTTP round-trip: rsp set, err nil.
+func rateLimited() fakeResult {
+	return fakeResult{rsp: &EmailResponse{HTTPStatus: 429}}
+}
+
+func TestOutboxEnqueuesOn429WithoutPanic(t *testing.T) {
+	provider := &fakeProvider{script: []fakeResult{rateLimited()}}
+	ob := NewOutbox(provider, NewMemoryStore())
+
+	rsp, err := ob.SendRegular("a@b.com", []string{"c@d.com"}, "subject", "text", "", "req-1")
+
+	if err == nil {
+		t.Fatal("expected an error for a 429 response, got nil")
+	}
+	if rsp == nil || rsp.HTTPStatus != 429 {
+		t.Fatalf("expected the 429 response to be returned alongside the error, got %+v", rsp)
+	}
+	if got := ob.Stats().Queued; got != 1 {
+		t.Fatalf("Stats().Queued = %d, want 1", got)
+	}
+}
+
+func TestOutboxRetrySendsOnNextAttempt(t *testing.T) {
+	store := NewMemoryStore()
+	provider := &fakeProvider{}
+	ob := NewOutbox(provider, store)
+
+	item := &OutboxItem{
+		ID:          "req-1",
+		Request:     OutboxRequest{Kind: "regular", From: "a@b.com", To: []string{"c@d.com"}, Subject: "s", RequestId: "req-1"},
+		Attempt:     1,
+		NextRetryAt: time.Now().Add(-time.Second),
+	}
+	if err := store.Enqueue(item); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	ob.stats.Queued = 1
+
+	ob.retry(item)
+
+	if got := ob.Stats(); got.Sent != 1 || got.Queued != 0 {
+		t.Fatalf("Stats() = %+v, want Sent=1 Queued=0", got)
+	}
+	if due, _ := store.Due(time.Now()); len(due) != 0 {
+		t.Fatalf("expected the item to be removed from the store after a successful retry, got %d left", len(due))
+	}
+}
+
+func TestOutboxRetryRequeuesOnPersistent429(t *testing.T) {
+	store := NewMemoryStore()
+	provider := &fakeProvider{script: []fakeResult{rateLimited()}}
+	ob := NewOutbox(provider, store)
+	ob.MaxAttempts = 8
+
+	item := &OutboxItem{
+		ID:      "req-1",
+		Request: OutboxRequest{Kind: "regular", From: "a@b.com", To: []string{"c@d.com"}, Subject: "s", RequestId: "req-1"},
+		Attempt: 1,
+	}
+	if err := store.Enqueue(item); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	ob.stats.Queued = 1
+
+	ob.retry(item)
+
+	if item.Attempt != 2 {
+		t.Fatalf("item.Attempt = %d, want 2", item.Attempt)
+	}
+	if item.LastError == "" {
+		t.Fatal("expected LastError to be set from the synthesized status error")
+	}
+	if got := ob.Stats(); got.Sent != 0 || got.Failed != 0 || got.Queued != 1 {
+		t.Fatalf("Stats() = %+v, want still queued and not counted as sent/failed", got)
+	}
+}
+
+func TestOutboxAbandonsAfterMaxAttempts(t *testing.T) {
+	store := NewMemoryStore()
+	provider := &fakeProvider{script: []fakeResult{rateLimited()}}
+	ob := NewOutbox(provider, store)
+	ob.MaxAttempts = 1
+
+	var abandoned *OutboxItem
+	ob.OnPermanentFailure = func(item *OutboxItem, err error) { abandoned = item }
+
+	item := &OutboxItem{
+		ID:      "req-1",
+		Request: OutboxRequest{Kind: "regular", From: "a@b.com", To: []string{"c@d.com"}, Subject: "s", RequestId: "req-1"},
+		Attempt: 1,
+	}
+	if err := store.Enqueue(item); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	ob.stats.Queued = 1
+
+	ob.retry(item)
+
+	if abandoned == nil || abandoned.ID != "req-1" {
+		t.Fatal("expected OnPermanentFailure to be called once MaxAttempts was exceeded")
+	}
+	if got := ob.Stats(); got.Failed != 1 || got.Queued != 0 {
+		t.Fatalf("Stats() = %+v, want Failed=1 Queued=0", got)
+	}
+	if due, _ := store.Due(time.Now()); len(due) != 0 {
+		t.Fatalf("expected the abandoned item to be removed from the store, got %d left", len(due))
+	}
+}
+
+func TestOutboxRetryAbandonsNonRetryableErrorImmediately(t *testing.T) {
+	store := NewMemoryStore()
+	provider := &fakeProvider{script: []fakeResult{{err: errors.New("permanent: bad from address")}}}
+	ob := NewOutbox(provider, store)
+	ob.MaxAttempts = 8
+
+	var abandoned bool
+	ob.OnPermanentFailure = func(item *OutboxItem, err error) { abandoned = true }
+
+	item := &OutboxItem{
+		ID:      "req-1",
+		Request: OutboxRequest{Kind: "regular", From: "a@b.com", To: []string{"c@d.com"}, Subject: "s", RequestId: "req-1"},
+		Attempt: 1,
+	}
+	if err := store.Enqueue(item); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	ob.stats.Queued = 1
+
+	ob.retry(item)
+
+	if !abandoned {
+		t.Fatal("expected a non-retryable error to abandon the item on the first retry")
+	}
+}
+
+func TestNewOutboxSeedsQueuedFromStore(t *testing.T) {
+	store := NewMemoryStore()
+	if err := store.Enqueue(&OutboxItem{ID: "req-1"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := store.Enqueue(&OutboxItem{ID: "req-2"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	ob := NewOutbox(&fakeProvider{}, store)
+
+	if got := ob.Stats().Queued; got != 2 {
+		t.Fatalf("Stats().Queued = %d, want 2 (seeded from the pre-populated store)", got)
+	}
+}
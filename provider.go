@@ -0,0 +1,44 @@
+package push
+
+import "fmt"
+
+// Provider is the common interface implemented by every email backend
+// (EngageLab, SMTP, ...). It lets callers swap or chain backends without
+// depending on a concrete client type.
+type Provider interface {
+	// Name identifies the provider for logging and error messages, e.g. "engagelab" or "smtp".
+	Name() string
+
+	SendRegular(from string, to []string, subject string, text, html string, reqId string) (*EmailResponse, error)
+
+	SendTemplate(from string, to []string, subject string, template string, vars map[string][]any, reqId string) (*EmailResponse, error)
+
+	GetTemplates() ([]*Template, error)
+}
+
+// TemplateSource resolves a template by invoke name. Providers that cannot
+// render templates on their own (e.g. SMTP) use it to fetch the HTML/Text/
+// Subject to render locally.
+type TemplateSource interface {
+	GetTemplate(invokeName string) (*Template, error)
+}
+
+// Name identifies this client among other Providers.
+func (c *EngageLabEmailClient) Name() string {
+	return "engagelab"
+}
+
+// GetTemplate implements TemplateSource by looking up a single template
+// from the full list returned by GetTemplates.
+func (c *EngageLabEmailClient) GetTemplate(invokeName string) (*Template, error) {
+	templates, err := c.GetTemplates()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range templates {
+		if t.TemplateInvokeName == invokeName {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("template %q not found", invokeName)
+}
@@ -0,0 +1,202 @@
+package push
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net/smtp"
+	"strings"
+	"text/template"
+)
+
+// SMTPConfig configures a plain SMTP Provider.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+
+	// From is used when a call site passes an empty "from" address.
+	From string
+
+	// UseTLS wraps the connection in TLS (port 465 style) instead of
+	// issuing STARTTLS, which net/smtp.SendMail already handles for 587/25.
+	UseTLS bool
+}
+
+// SMTPProvider sends mail over plain SMTP. It exists as a fallback for
+// when EngageLab is unreachable, so it takes the same arguments as the
+// EngageLab client's SendRegular/SendTemplate and renders templates
+// locally instead of calling a remote API. It does not build or accept
+// RegularEmail/TemplateEmail values.
+type SMTPProvider struct {
+	cfg       SMTPConfig
+	auth      smtp.Auth
+	templates TemplateSource
+}
+
+// NewSMTPProvider builds an SMTPProvider. templates may be nil if
+// SendTemplate is never called against it.
+func NewSMTPProvider(cfg SMTPConfig, templates TemplateSource) *SMTPProvider {
+	return &SMTPProvider{
+		cfg:       cfg,
+		auth:      smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host),
+		templates: templates,
+	}
+}
+
+func (p *SMTPProvider) Name() string {
+	return "smtp"
+}
+
+func (p *SMTPProvider) addr() string {
+	return fmt.Sprintf("%s:%d", p.cfg.Host, p.cfg.Port)
+}
+
+func (p *SMTPProvider) SendRegular(
+	from string,
+	to []string,
+	subject string,
+	text, html string,
+	reqId string,
+) (*EmailResponse, error) {
+	if from == "" {
+		from = p.cfg.From
+	}
+	if subject == "" {
+		return nil, fmt.Errorf("subject is required")
+	}
+	if html == "" && text == "" {
+		return nil, fmt.Errorf("html or text is required")
+	}
+
+	body := html
+	contentType := "text/html"
+	if body == "" {
+		body = text
+		contentType = "text/plain"
+	}
+
+	msg := buildMIMEMessage(from, to, subject, contentType, body)
+	if err := p.sendMail(from, to, msg); err != nil {
+		return nil, err
+	}
+	return &EmailResponse{HTTPStatus: 200, RequestId: reqId}, nil
+}
+
+func (p *SMTPProvider) SendTemplate(
+	from string,
+	to []string,
+	subject string,
+	templateName string,
+	vars map[string][]any,
+	reqId string,
+) (*EmailResponse, error) {
+	if p.templates == nil {
+		return nil, fmt.Errorf("smtp provider has no TemplateSource configured")
+	}
+	tpl, err := p.templates.GetTemplate(templateName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve template %q: %v", templateName, err)
+	}
+	if subject == "" {
+		subject = tpl.Subject
+	}
+
+	// vars is column-oriented (one slice per variable, aligned to `to`);
+	// the SMTP path sends a single combined message to every recipient,
+	// rendered with the first entry of each column. Later entries are
+	// ignored.
+	data := map[string]any{}
+	for k, v := range vars {
+		if len(v) > 0 {
+			data[k] = v[0]
+		}
+	}
+
+	// EngageLab templates use `%var%` placeholders, same as
+	// RenderTemplateLocal; text/template only applies on top for
+	// dynamic-vars-style logic, e.g. conditionals.
+	subject = substituteEngageLabVars(subject, data)
+
+	html, err := renderTextTemplate(substituteEngageLabVars(tpl.HTML, data), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render template html: %v", err)
+	}
+	text, err := renderTextTemplate(substituteEngageLabVars(tpl.Text, data), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render template text: %v", err)
+	}
+
+	return p.SendRegular(from, to, subject, text, html, reqId)
+}
+
+func (p *SMTPProvider) GetTemplates() ([]*Template, error) {
+	return nil, fmt.Errorf("smtp provider does not host templates")
+}
+
+func (p *SMTPProvider) sendMail(from string, to []string, msg []byte) error {
+	if !p.cfg.UseTLS {
+		return smtp.SendMail(p.addr(), p.auth, from, to, msg)
+	}
+
+	conn, err := tls.Dial("tcp", p.addr(), &tls.Config{ServerName: p.cfg.Host})
+	if err != nil {
+		return fmt.Errorf("failed to dial smtp over tls: %w", err)
+	}
+	defer conn.Close()
+
+	c, err := smtp.NewClient(conn, p.cfg.Host)
+	if err != nil {
+		return fmt.Errorf("failed to create smtp client: %v", err)
+	}
+	defer c.Close()
+
+	if p.auth != nil {
+		if err := c.Auth(p.auth); err != nil {
+			return fmt.Errorf("smtp auth failed: %v", err)
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func buildMIMEMessage(from string, to []string, subject, contentType, body string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: %s; charset=UTF-8\r\n\r\n", contentType)
+	buf.WriteString(body)
+	return buf.Bytes()
+}
+
+func renderTextTemplate(src string, data map[string]any) (string, error) {
+	if src == "" {
+		return "", nil
+	}
+	tmpl, err := template.New("body").Parse(src)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
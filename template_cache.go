@@ -0,0 +1,160 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// defaultTemplateCacheTTL is used by EngageLabEmailClient's lazily-created
+// cache; callers that want a different TTL can build their own with
+// NewTemplateCache and pass it wherever a TemplateSource is accepted.
+const defaultTemplateCacheTTL = 5 * time.Minute
+
+// TemplateCache holds the result of GetTemplates for up to TTL, so
+// previews, local rendering and the SMTP fallback provider don't need a
+// network round-trip per lookup.
+type TemplateCache struct {
+	ttl   time.Duration
+	fetch func() ([]*Template, error)
+
+	mu        sync.RWMutex
+	byName    map[string]*Template
+	fetchedAt time.Time
+}
+
+// NewTemplateCache wraps client.GetTemplates with a TTL cache.
+func NewTemplateCache(client *EngageLabEmailClient, ttl time.Duration) *TemplateCache {
+	return &TemplateCache{ttl: ttl, fetch: client.GetTemplates}
+}
+
+// Get returns a single template by invoke name, refreshing the cache first
+// if it is empty or older than TTL.
+func (c *TemplateCache) Get(invokeName string) (*Template, error) {
+	if err := c.refreshIfStale(); err != nil {
+		return nil, err
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	t, ok := c.byName[invokeName]
+	if !ok {
+		return nil, fmt.Errorf("template %q not found", invokeName)
+	}
+	return t, nil
+}
+
+// GetTemplate implements TemplateSource.
+func (c *TemplateCache) GetTemplate(invokeName string) (*Template, error) {
+	return c.Get(invokeName)
+}
+
+// List returns every cached template, refreshing first if stale.
+func (c *TemplateCache) List() ([]*Template, error) {
+	if err := c.refreshIfStale(); err != nil {
+		return nil, err
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	xs := make([]*Template, 0, len(c.byName))
+	for _, t := range c.byName {
+		xs = append(xs, t)
+	}
+	return xs, nil
+}
+
+func (c *TemplateCache) refreshIfStale() error {
+	c.mu.RLock()
+	stale := time.Since(c.fetchedAt) > c.ttl
+	c.mu.RUnlock()
+	if !stale {
+		return nil
+	}
+	return c.Refresh()
+}
+
+// Refresh fetches templates unconditionally, bypassing the TTL check.
+func (c *TemplateCache) Refresh() error {
+	templates, err := c.fetch()
+	if err != nil {
+		return fmt.Errorf("failed to refresh template cache: %v", err)
+	}
+
+	byName := make(map[string]*Template, len(templates))
+	for _, t := range templates {
+		byName[t.TemplateInvokeName] = t
+	}
+
+	c.mu.Lock()
+	c.byName = byName
+	c.fetchedAt = time.Now()
+	c.mu.Unlock()
+	return nil
+}
+
+// Start refreshes the cache in the background every TTL until ctx is canceled.
+func (c *TemplateCache) Start(ctx context.Context) {
+	ticker := time.NewTicker(c.ttl)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := c.Refresh(); err != nil {
+					log.Printf("template cache: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// templateCache lazily builds the client's own cache on first use.
+func (c *EngageLabEmailClient) templateCache() *TemplateCache {
+	c.cacheOnce.Do(func() {
+		c.cache = NewTemplateCache(c, defaultTemplateCacheTTL)
+	})
+	return c.cache
+}
+
+// RenderTemplateLocal renders a cached template without a network
+// round-trip, using EngageLab's own `%var%` substitution plus an optional
+// text/template pass for dynamicVars-style logic. It's meant for previews
+// and for unit-testing email content.
+func (c *EngageLabEmailClient) RenderTemplateLocal(invokeName string, vars map[string]any) (subject, html, text string, err error) {
+	tpl, err := c.templateCache().Get(invokeName)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	subject = substituteEngageLabVars(tpl.Subject, vars)
+
+	html, err = renderTextTemplate(substituteEngageLabVars(tpl.HTML, vars), vars)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to render html: %v", err)
+	}
+
+	text, err = renderTextTemplate(substituteEngageLabVars(tpl.Text, vars), vars)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to render text: %v", err)
+	}
+
+	return subject, html, text, nil
+}
+
+var engageLabVarPlaceholder = regexp.MustCompile(`%(\w+)%`)
+
+// substituteEngageLabVars replicates EngageLab's own `%var%` substitution
+// so local rendering matches what the API would send.
+func substituteEngageLabVars(src string, vars map[string]any) string {
+	return engageLabVarPlaceholder.ReplaceAllStringFunc(src, func(match string) string {
+		key := match[1 : len(match)-1]
+		if v, ok := vars[key]; ok {
+			return fmt.Sprintf("%v", v)
+		}
+		return match
+	})
+}
@@ -0,0 +1,22 @@
+package push
+
+import "testing"
+
+func TestSubstituteEngageLabVars(t *testing.T) {
+	got := substituteEngageLabVars("Hi %name%, your code is %code%", map[string]any{
+		"name": "Amos",
+		"code": 123456,
+	})
+	want := "Hi Amos, your code is 123456"
+	if got != want {
+		t.Fatalf("substituteEngageLabVars() = %q, want %q", got, want)
+	}
+}
+
+func TestSubstituteEngageLabVarsLeavesUnknownPlaceholders(t *testing.T) {
+	got := substituteEngageLabVars("Hi %name%", map[string]any{})
+	want := "Hi %name%"
+	if got != want {
+		t.Fatalf("substituteEngageLabVars() = %q, want %q (unmatched placeholders left alone)", got, want)
+	}
+}
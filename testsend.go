@@ -0,0 +1,30 @@
+package push
+
+import (
+	"fmt"
+	"os"
+)
+
+// fromEnv names the environment variable holding the verified sender
+// address TestSend uses, since it takes
+// no "from" of its own and this package otherwise never stores a default.
+const fromEnv = "ENGAGE_LAB_EMAIL_FROM"
+
+// TestSend sends a sandboxed canary email to to: Settings.Sandbox is set
+// so EngageLab validates the request without actually delivering it,
+// making it cheap to check that an API key and from-domain are wired up
+// correctly.
+func (c *EngageLabEmailClient) TestSend(to string) (*EmailResponse, error) {
+	from := os.Getenv(fromEnv)
+	if from == "" {
+		return nil, fmt.Errorf("environment variable %s is not set; TestSend needs a verified from address", fromEnv)
+	}
+
+	bodyCommon := &BodyCommon{
+		Settings: &Settings{Sandbox: true, ReturnEmailID: true},
+	}
+	subject := "engagelab-emailctl test-send"
+	text := "This is a sandboxed test-send used to validate API key and from-domain configuration; it is not delivered."
+
+	return c.Send(bodyCommon, from, []string{to}, subject, text, "", "", "", "")
+}
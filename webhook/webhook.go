@@ -0,0 +1,163 @@
+// Package webhook parses EngageLab's delivery event callbacks and
+// dispatches them to handlers registered by the RequestId or CustomArgs
+// an outbound send was tagged with.
+package webhook
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+)
+
+// EventType is one of the delivery lifecycle events EngageLab posts back.
+type EventType string
+
+const (
+	EventDelivered    EventType = "delivered"
+	EventBounced      EventType = "bounced"
+	EventOpened       EventType = "opened"
+	EventClicked      EventType = "clicked"
+	EventUnsubscribed EventType = "unsubscribed"
+	EventComplained   EventType = "complained"
+)
+
+// SignatureHeader is the header EngageLab sets with the shared secret used
+// to authenticate the callback.
+const SignatureHeader = "X-Engagelab-Signature"
+
+// Event is a single delivery event for one recipient of one sent email.
+type Event struct {
+	Type       EventType      `json:"event"`
+	RequestId  string         `json:"request_id,omitempty"`
+	EmailId    string         `json:"email_id,omitempty"`
+	Email      string         `json:"email,omitempty"`
+	Timestamp  int64          `json:"timestamp,omitempty"`
+	CustomArgs map[string]any `json:"custom_args,omitempty"`
+
+	// Reason carries the bounce/complaint reason, when present.
+	Reason string `json:"reason,omitempty"`
+	// URL is the link target for "clicked" events.
+	URL string `json:"url,omitempty"`
+}
+
+// Handler is called once per Event that matches a registration.
+type Handler func(Event)
+
+// Receiver is an http.Handler that verifies and dispatches EngageLab
+// webhook callbacks. Construct with NewReceiver and mount at whatever path
+// EngageLab is configured to call.
+type Receiver struct {
+	secret string
+
+	mu          sync.RWMutex
+	any         []Handler
+	byRequestId map[string][]Handler
+	byCustomArg map[string][]Handler
+}
+
+// NewReceiver builds a Receiver that rejects callbacks whose
+// X-Engagelab-Signature header does not match secret. An empty secret
+// disables verification and accepts every callback.
+func NewReceiver(secret string) *Receiver {
+	return &Receiver{
+		secret:      secret,
+		byRequestId: map[string][]Handler{},
+		byCustomArg: map[string][]Handler{},
+	}
+}
+
+// OnAny registers a handler called for every event, regardless of RequestId/CustomArgs.
+func (r *Receiver) OnAny(h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.any = append(r.any, h)
+}
+
+// OnRequestId registers a handler called only for events whose RequestId matches.
+func (r *Receiver) OnRequestId(requestId string, h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byRequestId[requestId] = append(r.byRequestId[requestId], h)
+}
+
+// OnCustomArg registers a handler called only for events whose CustomArgs[key] == value.
+func (r *Receiver) OnCustomArg(key, value string, h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byCustomArg[customArgKey(key, value)] = append(r.byCustomArg[customArgKey(key, value)], h)
+}
+
+func customArgKey(key, value string) string {
+	return key + "=" + value
+}
+
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !r.verify(req) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	events, err := parseEvents(body)
+	if err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	for _, event := range events {
+		r.dispatch(event)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// parseEvents accepts either a single event object or a JSON array of
+// events, matching how most webhook providers batch callbacks.
+func parseEvents(body []byte) ([]Event, error) {
+	var events []Event
+	if err := json.Unmarshal(body, &events); err == nil {
+		return events, nil
+	}
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+	return []Event{event}, nil
+}
+
+func (r *Receiver) verify(req *http.Request) bool {
+	if r.secret == "" {
+		return true
+	}
+	got := req.Header.Get(SignatureHeader)
+	return subtle.ConstantTimeCompare([]byte(got), []byte(r.secret)) == 1
+}
+
+func (r *Receiver) dispatch(event Event) {
+	r.mu.RLock()
+	handlers := append([]Handler{}, r.any...)
+	if event.RequestId != "" {
+		handlers = append(handlers, r.byRequestId[event.RequestId]...)
+	}
+	for k, v := range event.CustomArgs {
+		if s, ok := v.(string); ok {
+			handlers = append(handlers, r.byCustomArg[customArgKey(k, s)]...)
+		}
+	}
+	r.mu.RUnlock()
+
+	if len(handlers) == 0 {
+		log.Printf("webhook: no handler registered for event %s (request_id=%s)", event.Type, event.RequestId)
+		return
+	}
+	for _, h := range handlers {
+		h(event)
+	}
+}
@@ -0,0 +1,35 @@
+package webhook
+
+import "testing"
+
+func TestParseEventsArray(t *testing.T) {
+	events, err := parseEvents([]byte(`[{"event":"delivered","request_id":"req-1"},{"event":"bounced","request_id":"req-2"}]`))
+	if err != nil {
+		t.Fatalf("parseEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if events[0].Type != EventDelivered || events[0].RequestId != "req-1" {
+		t.Fatalf("events[0] = %+v", events[0])
+	}
+	if events[1].Type != EventBounced || events[1].RequestId != "req-2" {
+		t.Fatalf("events[1] = %+v", events[1])
+	}
+}
+
+func TestParseEventsSingleObject(t *testing.T) {
+	events, err := parseEvents([]byte(`{"event":"opened","request_id":"req-1"}`))
+	if err != nil {
+		t.Fatalf("parseEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != EventOpened {
+		t.Fatalf("events = %+v", events)
+	}
+}
+
+func TestParseEventsInvalidPayload(t *testing.T) {
+	if _, err := parseEvents([]byte(`not json`)); err == nil {
+		t.Fatal("expected an error for an invalid payload")
+	}
+}